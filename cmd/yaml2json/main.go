@@ -0,0 +1,167 @@
+// Command yaml2json converts YAML documents to JSON (and, with -reverse,
+// JSON back to YAML). See pkg/yaml2json for the underlying library.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/paulvollmer/yaml2json/pkg/yaml2json"
+)
+
+var (
+	output   string
+	reverse  bool
+	format   string
+	sortKeys bool
+	ndjson   bool
+	split    bool
+	indent   int
+	compact  bool
+	schema   string
+	headers  headerFlag
+	netrc    bool
+	retry    int
+	cacheDir string
+	insecure bool
+)
+
+func init() {
+	flag.StringVar(&output, "output", "", "Write to the file instead of to stdout")
+	flag.BoolVar(&reverse, "reverse", false, "Convert JSON input to YAML output instead of YAML to JSON")
+	flag.StringVar(&format, "format", "", "Input format: yaml, json or auto (detect from file extension or content). Defaults to json with -reverse, yaml otherwise")
+	flag.BoolVar(&sortKeys, "sort-keys", false, "Sort YAML mapping keys alphabetically instead of preserving document order")
+	flag.BoolVar(&ndjson, "ndjson", false, "Emit a multi-document YAML stream as newline-delimited JSON instead of a JSON array")
+	flag.BoolVar(&split, "split", false, "Write each document of a multi-document YAML stream to its own file named by -output (a directory or a %d template)")
+	flag.IntVar(&indent, "indent", 0, "Pretty-print JSON output using this many spaces (0 for compact)")
+	flag.BoolVar(&compact, "compact", false, "Force compact JSON output even if -indent is set")
+	flag.StringVar(&schema, "schema", "", "Validate the input against the JSON Schema at this path or URL before converting")
+	flag.Var(&headers, "header", "Add a request header as K=V to HTTP(S) loads (repeatable)")
+	flag.BoolVar(&netrc, "netrc", false, "Send credentials for the request host found in ~/.netrc (or $NETRC)")
+	flag.IntVar(&retry, "retry", 0, "Retry HTTP(S) loads this many times, with exponential backoff, on 5xx responses")
+	flag.StringVar(&cacheDir, "cache-dir", "", "Cache HTTP(S) loads in this directory, revalidated via ETag/Last-Modified")
+	flag.BoolVar(&insecure, "insecure", false, "Skip TLS certificate verification for HTTP(S) loads")
+}
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage of %s:\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s [YAML OR JSON FILE OR URL]\n\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() < 1 {
+		fmt.Fprintln(os.Stderr, "you need to provide the file path or url to load")
+		os.Exit(1)
+	}
+	path := flag.Arg(0)
+
+	loader := &yaml2json.HTTPLoader{
+		Headers:  http.Header(headers),
+		Netrc:    netrc,
+		Retry:    retry,
+		CacheDir: cacheDir,
+		Insecure: insecure,
+	}
+
+	opts := yaml2json.Options{
+		Format:   format,
+		Reverse:  reverse,
+		SortKeys: sortKeys,
+		NDJSON:   ndjson,
+		Indent:   indent,
+		Compact:  compact,
+		Loader:   yaml2json.LoaderFunc(loader.LoadFromFileOrHTTP),
+	}
+
+	if schema != "" {
+		data, err := loader.LoadFromFileOrHTTP(path)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+		schemaData, err := loader.LoadFromFileOrHTTP(schema)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+		if opts.Format == "auto" {
+			switch strings.ToLower(filepath.Ext(path)) {
+			case ".json":
+				opts.Format = "json"
+			case ".yaml", ".yml":
+				opts.Format = "yaml"
+			}
+		}
+		doc, violations, err := yaml2json.ValidateAndConvert(data, schemaData, opts)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+		if len(violations) > 0 {
+			for _, v := range violations {
+				fmt.Fprintf(os.Stderr, "%s: %s\n", v.Pointer, v.Message)
+			}
+			os.Exit(1)
+		}
+		writeOutput(doc)
+		return
+	}
+
+	if split {
+		data, err := loader.LoadFromFileOrHTTP(path)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+		if err := yaml2json.SplitDocs(data, output, sortKeys); err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+		return
+	}
+
+	doc, err := yaml2json.ConvertPath(path, opts)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+
+	writeOutput(doc)
+}
+
+func writeOutput(doc []byte) {
+	if output == "" {
+		fmt.Println(string(doc))
+		return
+	}
+	if err := ioutil.WriteFile(output, doc, 0600); err != nil { // umask settings will be respected this way
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+}
+
+// headerFlag collects repeated -header K=V flags into an http.Header.
+type headerFlag http.Header
+
+func (h *headerFlag) String() string {
+	return ""
+}
+
+func (h *headerFlag) Set(value string) error {
+	parts := strings.SplitN(value, "=", 2)
+	if len(parts) != 2 {
+		return fmt.Errorf("invalid -header %q, want K=V", value)
+	}
+	if *h == nil {
+		*h = headerFlag{}
+	}
+	http.Header(*h).Add(parts[0], parts[1])
+	return nil
+}
@@ -0,0 +1,89 @@
+package yaml2json
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+// ValidationError describes a single JSON Schema violation.
+type ValidationError struct {
+	// Pointer is the JSON pointer (e.g. "/spec/replicas") of the value
+	// that failed validation.
+	Pointer string `json:"pointer"`
+	// Message describes why the value failed validation.
+	Message string `json:"message"`
+}
+
+// ValidateAndConvert converts the YAML document in data to JSON, validates
+// it against the given JSON Schema (Draft 7 / 2019-09 / 2020-12, detected
+// from the schema's "$schema" keyword), and returns the converted JSON
+// alongside any violations. doc is rendered according to opts, so it
+// honors -indent, -ndjson, -sort-keys, and -reverse the same way a plain
+// conversion would; validation itself always runs against the document's
+// plain JSON form, since a JSON Schema has no notion of NDJSON framing or
+// YAML output. doc is non-nil whenever conversion succeeded, even if
+// validation failed, so callers can inspect both.
+func ValidateAndConvert(data []byte, schema []byte, opts Options) (doc json.RawMessage, violations []ValidationError, err error) {
+	validationJSON, err := plainJSON(data, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var v interface{}
+	if err := json.Unmarshal(validationJSON, &v); err != nil {
+		return nil, nil, err
+	}
+
+	compiler := jsonschema.NewCompiler()
+	if err := compiler.AddResource("schema.json", bytes.NewReader(schema)); err != nil {
+		return nil, nil, err
+	}
+	sch, err := compiler.Compile("schema.json")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := sch.Validate(v); err != nil {
+		ve, ok := err.(*jsonschema.ValidationError)
+		if !ok {
+			return nil, nil, err
+		}
+		violations = leafViolations(ve)
+	}
+
+	doc, err = ConvertBytes(data, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return doc, violations, nil
+}
+
+// plainJSON renders data as compact JSON for schema validation,
+// regardless of opts.NDJSON/Indent/SortKeys/Reverse. The input is decoded
+// with the same format ConvertBytes would use for opts, so an unset Format
+// means JSON when opts.Reverse is set and YAML otherwise.
+func plainJSON(data []byte, opts Options) (json.RawMessage, error) {
+	format := opts.Format
+	if format == "" && opts.Reverse {
+		format = "json"
+	}
+	return ConvertBytes(data, Options{Format: format})
+}
+
+// leafViolations flattens a jsonschema.ValidationError tree into its leaf
+// violations, skipping the generic "doesn't validate with ..." summary
+// nodes that wrap each branch.
+func leafViolations(ve *jsonschema.ValidationError) []ValidationError {
+	if len(ve.Causes) == 0 {
+		return []ValidationError{{Pointer: ve.InstanceLocation, Message: ve.Message}}
+	}
+
+	var violations []ValidationError
+	for _, cause := range ve.Causes {
+		violations = append(violations, leafViolations(cause)...)
+	}
+	return violations
+}
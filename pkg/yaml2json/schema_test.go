@@ -0,0 +1,57 @@
+package yaml2json
+
+import (
+	"strings"
+	"testing"
+)
+
+const testSchema = `{
+	"$schema": "http://json-schema.org/draft-07/schema#",
+	"type": "object",
+	"required": ["name", "age"],
+	"properties": {
+		"name": {"type": "string"},
+		"age": {"type": "integer", "minimum": 0}
+	}
+}`
+
+func TestValidateAndConvert(t *testing.T) {
+	tests := []struct {
+		name           string
+		doc            string
+		wantViolations int
+	}{
+		{"valid", "name: Ada\nage: 36\n", 0},
+		{"missing required field", "name: Ada\n", 1},
+		{"wrong type", "name: Ada\nage: old\n", 1},
+		{"multiple violations", "age: -1\n", 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, violations, err := ValidateAndConvert([]byte(tt.doc), []byte(testSchema), Options{})
+			if err != nil {
+				t.Fatalf("ValidateAndConvert: %v", err)
+			}
+			if doc == nil {
+				t.Errorf("doc is nil, want the converted document regardless of violations")
+			}
+			if len(violations) != tt.wantViolations {
+				t.Errorf("got %d violations, want %d: %v", len(violations), tt.wantViolations, violations)
+			}
+		})
+	}
+}
+
+func TestValidateAndConvertComposesWithOutputOptions(t *testing.T) {
+	doc, violations, err := ValidateAndConvert([]byte("name: Ada\nage: 36\n"), []byte(testSchema), Options{Indent: 2})
+	if err != nil {
+		t.Fatalf("ValidateAndConvert: %v", err)
+	}
+	if len(violations) != 0 {
+		t.Fatalf("unexpected violations: %v", violations)
+	}
+	want := "{\n  \"name\": \"Ada\",\n  \"age\": 36\n}"
+	if strings.TrimRight(string(doc), "\n") != want {
+		t.Errorf("-indent ignored: got %q, want %q", doc, want)
+	}
+}
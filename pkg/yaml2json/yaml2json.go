@@ -0,0 +1,642 @@
+// Package yaml2json converts between YAML and JSON documents, preserving
+// YAML mapping key order and supporting multi-document streams. It backs
+// the yaml2json CLI in cmd/yaml2json, but is usable as a library on its own.
+package yaml2json
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+// LoadHTTPTimeout is the default timeout used by HTTPLoader.
+const LoadHTTPTimeout = 30 * time.Second
+
+// Options controls how Convert and ConvertBytes interpret input and render
+// output. The zero value converts a single YAML document to compact JSON,
+// preserving mapping key order.
+type Options struct {
+	// Format selects how the input is decoded: "yaml", "json", or "auto"
+	// to detect it from content. It only describes the input; it does
+	// not select the output direction, which Reverse controls
+	// independently. Left empty, it defaults to "json" when Reverse is
+	// set and "yaml" otherwise.
+	Format string
+	// Reverse selects YAML output instead of JSON output.
+	Reverse bool
+	// SortKeys sorts YAML mapping keys alphabetically instead of
+	// preserving the original document order.
+	SortKeys bool
+	// NDJSON emits a multi-document YAML stream as newline-delimited JSON
+	// instead of a JSON array. Ignored when Reverse converts to YAML.
+	NDJSON bool
+	// Indent, when non-zero, pretty-prints JSON output using that many
+	// spaces. Ignored when Compact is true or when converting to YAML.
+	Indent int
+	// Compact forces compact JSON output even when Indent is set.
+	Compact bool
+	// Loader fetches the bytes for a path passed to ConvertPath. The zero
+	// value uses FileOrHTTPLoader.
+	Loader Loader
+}
+
+// Loader fetches the raw bytes for a document identified by path. Callers
+// can implement it to source documents from anywhere (S3, embed.FS, stdin)
+// instead of being limited to FileOrHTTPLoader's file-or-http switch.
+type Loader interface {
+	Load(path string) ([]byte, error)
+}
+
+// LoaderFunc adapts a function to a Loader.
+type LoaderFunc func(path string) ([]byte, error)
+
+// Load calls f(path).
+func (f LoaderFunc) Load(path string) ([]byte, error) {
+	return f(path)
+}
+
+// FileOrHTTPLoader is the default Loader: it reads from the local
+// filesystem unless path starts with "http", in which case it fetches it
+// over HTTP with LoadHTTPTimeout.
+var FileOrHTTPLoader Loader = LoaderFunc(LoadFromFileOrHTTP)
+
+// Convert reads a document from r, converts it according to opts, and
+// writes the result to w.
+func Convert(r io.Reader, w io.Writer, opts Options) error {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	out, err := ConvertBytes(data, opts)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(out)
+	return err
+}
+
+// ConvertBytes converts data according to opts and returns the result.
+// Format selects how the input bytes are decoded; Reverse independently
+// selects the output direction (YAML when true, JSON otherwise). An unset
+// Format defaults to "json" when Reverse is set and "yaml" otherwise, so
+// plain -reverse usage keeps working without also requiring -format json.
+func ConvertBytes(data []byte, opts Options) ([]byte, error) {
+	inputFormat := opts.Format
+	if inputFormat == "" {
+		if opts.Reverse {
+			inputFormat = "json"
+		} else {
+			inputFormat = "yaml"
+		}
+	}
+	if inputFormat == "auto" {
+		inputFormat = detectFormat("", data)
+	}
+
+	if inputFormat == "json" {
+		jsonDoc, err := bytesToJSONDoc(data)
+		if err != nil {
+			return nil, err
+		}
+		if opts.Reverse {
+			return JSONToYAML(jsonDoc)
+		}
+		out, err := json.Marshal(jsonDoc)
+		if err != nil {
+			return nil, err
+		}
+		return indentJSON(out, opts)
+	}
+
+	docs, err := bytesToYAMLDoc(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var out json.RawMessage
+	if opts.NDJSON && !opts.Reverse {
+		out, err = yamlDocsToNDJSON(docs, opts.SortKeys)
+	} else {
+		out, err = yamlDocsToJSON(docs, opts.SortKeys)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Reverse {
+		var v interface{}
+		if err := json.Unmarshal(out, &v); err != nil {
+			return nil, err
+		}
+		return JSONToYAML(v)
+	}
+
+	return indentJSON(out, opts)
+}
+
+// ConvertPath loads the document at path with opts.Loader (FileOrHTTPLoader
+// by default) and converts it according to opts.
+func ConvertPath(path string, opts Options) ([]byte, error) {
+	loader := opts.Loader
+	if loader == nil {
+		loader = FileOrHTTPLoader
+	}
+
+	data, err := loader.Load(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Format == "auto" {
+		opts.Format = detectFormat(path, data)
+	}
+
+	return ConvertBytes(data, opts)
+}
+
+func indentJSON(data json.RawMessage, opts Options) ([]byte, error) {
+	if opts.Compact || opts.Indent <= 0 {
+		return data, nil
+	}
+
+	if opts.NDJSON {
+		return indentNDJSON(data, opts.Indent)
+	}
+
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, data, "", strings.Repeat(" ", opts.Indent)); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// indentNDJSON pretty-prints each line of a newline-delimited JSON stream
+// independently, since json.Indent chokes on NDJSON's multiple top-level
+// values.
+func indentNDJSON(data []byte, indent int) ([]byte, error) {
+	lines := bytes.Split(bytes.TrimRight(data, "\n"), []byte("\n"))
+
+	var buf bytes.Buffer
+	for i, line := range lines {
+		if i > 0 {
+			buf.WriteByte('\n')
+		}
+		if err := json.Indent(&buf, line, "", strings.Repeat(" ", indent)); err != nil {
+			return nil, err
+		}
+	}
+	buf.WriteByte('\n')
+	return buf.Bytes(), nil
+}
+
+// detectFormat determines whether path points to a YAML or JSON document,
+// first by file extension and falling back to sniffing the content.
+func detectFormat(path string, data []byte) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return "json"
+	case ".yaml", ".yml":
+		return "yaml"
+	}
+
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[') {
+		return "json"
+	}
+	return "yaml"
+}
+
+// LoadFromFileOrHTTP loads the bytes from a file or a remote http server
+// based on the path passed in, using a default HTTPLoader with no extra
+// headers, retries, or caching. See HTTPLoader for a configurable loader.
+func LoadFromFileOrHTTP(path string) ([]byte, error) {
+	return defaultHTTPLoader.LoadFromFileOrHTTP(path)
+}
+
+// LoadStrategy returns a loader function for a given path or uri
+func LoadStrategy(path string, local, remote func(string) ([]byte, error)) func(string) ([]byte, error) {
+	if strings.HasPrefix(path, "http") {
+		return remote
+	}
+	return local
+}
+
+// YAMLToJSON walks a parsed YAML node tree and renders it as JSON, preserving
+// the original order of mapping keys and honoring the resolved YAML tag for
+// scalars instead of going through a lossy interface{} type switch.
+func YAMLToJSON(node *yaml.Node) (json.RawMessage, error) {
+	w := &jsonWriter{}
+	var buf bytes.Buffer
+	if err := w.writeNode(&buf, node); err != nil {
+		return nil, err
+	}
+	return json.RawMessage(buf.Bytes()), nil
+}
+
+// JSONToYAML converts JSON unmarshaled data into yaml compatible data
+func JSONToYAML(data interface{}) ([]byte, error) {
+	return yaml.Marshal(data)
+}
+
+// bytesToYAMLDoc decodes every document in a (possibly multi-document,
+// "---"-separated) YAML stream.
+func bytesToYAMLDoc(data []byte) ([]*yaml.Node, error) {
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+
+	var docs []*yaml.Node
+	for {
+		var document yaml.Node
+		if err := dec.Decode(&document); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, err
+		}
+		docs = append(docs, &document)
+	}
+
+	return docs, nil
+}
+
+func bytesToJSONDoc(data []byte) (interface{}, error) {
+	var document interface{}
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, err
+	}
+
+	return document, nil
+}
+
+// yamlDocsToJSON renders a decoded YAML stream as JSON: an empty stream
+// (empty or comment-only input) yields null, a single document yields
+// that document's JSON value, and a multi-document stream yields a JSON
+// array of each document's value.
+func yamlDocsToJSON(docs []*yaml.Node, sortKeys bool) (json.RawMessage, error) {
+	w := &jsonWriter{sortKeys: sortKeys}
+
+	if len(docs) == 0 {
+		return json.RawMessage("null"), nil
+	}
+
+	if len(docs) == 1 {
+		var buf bytes.Buffer
+		if err := w.writeNode(&buf, docs[0]); err != nil {
+			return nil, err
+		}
+		return json.RawMessage(buf.Bytes()), nil
+	}
+
+	var buf bytes.Buffer
+	buf.WriteByte('[')
+	for i, doc := range docs {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if err := w.writeNode(&buf, doc); err != nil {
+			return nil, err
+		}
+	}
+	buf.WriteByte(']')
+	return json.RawMessage(buf.Bytes()), nil
+}
+
+// yamlDocsToNDJSON renders a decoded YAML stream as newline-delimited JSON,
+// one object per document.
+func yamlDocsToNDJSON(docs []*yaml.Node, sortKeys bool) ([]byte, error) {
+	w := &jsonWriter{sortKeys: sortKeys}
+
+	var buf bytes.Buffer
+	for _, doc := range docs {
+		var line bytes.Buffer
+		if err := w.writeNode(&line, doc); err != nil {
+			return nil, err
+		}
+		buf.Write(line.Bytes())
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes(), nil
+}
+
+// SplitDocs writes each document in a multi-document YAML stream to its own
+// JSON file, either inside the directory named by output or using output as
+// a fmt template such as "out-%d.json".
+func SplitDocs(data []byte, output string, sortKeys bool) error {
+	if output == "" {
+		return fmt.Errorf("split requires output to name a directory or a %%d template")
+	}
+
+	docs, err := bytesToYAMLDoc(data)
+	if err != nil {
+		return err
+	}
+
+	w := &jsonWriter{sortKeys: sortKeys}
+	for i, doc := range docs {
+		path, err := splitOutputPath(output, i)
+		if err != nil {
+			return err
+		}
+		var buf bytes.Buffer
+		if err := w.writeNode(&buf, doc); err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(path, buf.Bytes(), 0600); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func splitOutputPath(output string, index int) (string, error) {
+	if strings.Contains(output, "%d") {
+		return fmt.Sprintf(output, index), nil
+	}
+
+	info, err := os.Stat(output)
+	if err != nil {
+		return "", err
+	}
+	if !info.IsDir() {
+		return "", fmt.Errorf("split output %q is neither a directory nor a %%d template", output)
+	}
+	return filepath.Join(output, fmt.Sprintf("%d.json", index)), nil
+}
+
+// jsonWriter renders yaml.Node trees as JSON.
+type jsonWriter struct {
+	sortKeys bool
+}
+
+func (w *jsonWriter) writeNode(buf *bytes.Buffer, node *yaml.Node) error {
+	if node == nil {
+		buf.WriteString("null")
+		return nil
+	}
+
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) == 0 {
+			buf.WriteString("null")
+			return nil
+		}
+		return w.writeNode(buf, node.Content[0])
+	case yaml.MappingNode:
+		return w.writeMapping(buf, node)
+	case yaml.SequenceNode:
+		buf.WriteByte('[')
+		for i, item := range node.Content {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := w.writeNode(buf, item); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+		return nil
+	case yaml.ScalarNode:
+		return writeScalarAsJSON(buf, node)
+	case yaml.AliasNode:
+		return w.writeNode(buf, node.Alias)
+	default:
+		return fmt.Errorf("unsupported yaml node kind: %v", node.Kind)
+	}
+}
+
+// mergeTag is the tag yaml.v3 resolves a "<<" merge key to.
+const mergeTag = "!!merge"
+
+// mappingPair pairs a mapping key with its value.
+type mappingPair struct {
+	key   *yaml.Node
+	value *yaml.Node
+}
+
+// writeMapping renders a MappingNode's Content (keys at even indices, values
+// at odd indices) as a JSON object, preserving document order unless
+// sortKeys requests the old alphabetical behavior.
+func (w *jsonWriter) writeMapping(buf *bytes.Buffer, node *yaml.Node) error {
+	pairs, err := resolveMappingPairs(node)
+	if err != nil {
+		return err
+	}
+
+	if w.sortKeys {
+		sort.Slice(pairs, func(i, j int) bool {
+			return pairs[i].key.Value < pairs[j].key.Value
+		})
+	}
+
+	buf.WriteByte('{')
+	for i, p := range pairs {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(p.key.Value)
+		if err != nil {
+			return err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		if err := w.writeNode(buf, p.value); err != nil {
+			return err
+		}
+	}
+	buf.WriteByte('}')
+	return nil
+}
+
+// resolveMappingPairs walks a MappingNode's Content, expanding "<<" merge
+// keys in place and collapsing duplicate keys, so the result matches what
+// the baseline map-based conversion produced: explicit keys always
+// override merged ones regardless of where they appear, and a duplicate
+// explicit key collapses to its last literal value at the position of its
+// first occurrence.
+func resolveMappingPairs(node *yaml.Node) ([]mappingPair, error) {
+	finalValue := map[string]*yaml.Node{}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		if key.Tag == mergeTag {
+			continue
+		}
+		finalValue[key.Value] = value
+	}
+
+	var pairs []mappingPair
+	emitted := map[string]bool{}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+
+		if key.Tag == mergeTag {
+			merged, err := resolveMergeValue(value)
+			if err != nil {
+				return nil, err
+			}
+			for _, p := range merged {
+				if emitted[p.key.Value] {
+					continue
+				}
+				if _, explicit := finalValue[p.key.Value]; explicit {
+					continue
+				}
+				pairs = append(pairs, p)
+				emitted[p.key.Value] = true
+			}
+			continue
+		}
+
+		if emitted[key.Value] {
+			continue
+		}
+		pairs = append(pairs, mappingPair{key, finalValue[key.Value]})
+		emitted[key.Value] = true
+	}
+
+	return pairs, nil
+}
+
+// resolveMergeValue resolves a "<<" merge key's value to the key/value
+// pairs it contributes: a single (usually aliased) mapping, or a sequence
+// of them, in which case earlier mappings in the sequence override keys
+// from later ones, per the YAML merge key spec.
+func resolveMergeValue(value *yaml.Node) ([]mappingPair, error) {
+	switch value.Kind {
+	case yaml.AliasNode:
+		return resolveMergeValue(value.Alias)
+	case yaml.MappingNode:
+		return resolveMappingPairs(value)
+	case yaml.SequenceNode:
+		seen := map[string]bool{}
+		var merged []mappingPair
+		for _, item := range value.Content {
+			pairs, err := resolveMergeValue(item)
+			if err != nil {
+				return nil, err
+			}
+			for _, p := range pairs {
+				if seen[p.key.Value] {
+					continue
+				}
+				merged = append(merged, p)
+				seen[p.key.Value] = true
+			}
+		}
+		return merged, nil
+	default:
+		return nil, fmt.Errorf("merge key value must be a mapping or a sequence of mappings, got %q (line %d)", value.Tag, value.Line)
+	}
+}
+
+// writeScalarAsJSON renders a ScalarNode as JSON according to its resolved
+// tag, rather than Go's interface{} type switch, so e.g. "1" stays an
+// integer and "1.0" stays a float.
+func writeScalarAsJSON(buf *bytes.Buffer, node *yaml.Node) error {
+	switch node.Tag {
+	case "!!null":
+		buf.WriteString("null")
+		return nil
+	case "!!bool":
+		b, err := strconv.ParseBool(node.Value)
+		if err != nil {
+			return err
+		}
+		buf.WriteString(strconv.FormatBool(b))
+		return nil
+	case "!!int":
+		i, err := strconv.ParseInt(node.Value, 0, 64)
+		if err != nil {
+			// too big for int64 (e.g. arbitrary precision YAML ints); emit verbatim
+			buf.WriteString(node.Value)
+			return nil
+		}
+		buf.WriteString(strconv.FormatInt(i, 10))
+		return nil
+	case "!!float":
+		if isYAMLInfOrNaN(node.Value) {
+			return fmt.Errorf("cannot convert YAML float %q to JSON: JSON has no Infinity/NaN literal (line %d)", node.Value, node.Line)
+		}
+		f, err := strconv.ParseFloat(node.Value, 64)
+		if err != nil {
+			return err
+		}
+		// emit verbatim, like the oversized !!int case above, so e.g.
+		// "1.0" stays "1.0" instead of collapsing to the integer "1";
+		// YAML spellings that aren't JSON numbers (".5", "+1.5") are
+		// reformatted
+		if json.Valid([]byte(node.Value)) {
+			buf.WriteString(node.Value)
+		} else {
+			buf.WriteString(strconv.FormatFloat(f, 'g', -1, 64))
+		}
+		return nil
+	default:
+		b, err := json.Marshal(node.Value)
+		if err != nil {
+			return err
+		}
+		buf.Write(b)
+		return nil
+	}
+}
+
+// isYAMLInfOrNaN reports whether value is one of the YAML 1.1 float
+// literals for infinity or not-a-number (".inf", "+.inf", "-.inf",
+// ".nan", in any case), none of which have a JSON representation.
+func isYAMLInfOrNaN(value string) bool {
+	v := strings.ToLower(strings.TrimPrefix(strings.TrimPrefix(value, "+"), "-"))
+	return v == ".inf" || v == ".nan"
+}
+
+// YAMLDoc loads a (possibly multi-document) yaml stream from either http or
+// a file and converts it to json. A single document yields its JSON value
+// directly; multiple documents yield a JSON array.
+func YAMLDoc(path string) (json.RawMessage, error) {
+	docs, err := YAMLData(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return yamlDocsToJSON(docs, false)
+}
+
+// YAMLData loads a yaml stream from either http or a file
+func YAMLData(path string) ([]*yaml.Node, error) {
+	data, err := LoadFromFileOrHTTP(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return bytesToYAMLDoc(data)
+}
+
+// JSONDoc loads a json document from either http or a file and converts it to yaml
+func JSONDoc(path string) ([]byte, error) {
+	jsonDoc, err := JSONData(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return JSONToYAML(jsonDoc)
+}
+
+// JSONData loads a json document from either http or a file
+func JSONData(path string) (interface{}, error) {
+	data, err := LoadFromFileOrHTTP(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return bytesToJSONDoc(data)
+}
@@ -0,0 +1,161 @@
+package yaml2json
+
+import (
+	"strings"
+	"testing"
+)
+
+func convertYAML(t *testing.T, in string, opts Options) string {
+	t.Helper()
+	out, err := ConvertBytes([]byte(in), opts)
+	if err != nil {
+		t.Fatalf("ConvertBytes(%q): %v", in, err)
+	}
+	return string(out)
+}
+
+func TestConvertBytesPreservesMappingOrder(t *testing.T) {
+	in := "zebra: 1\napple: 2\nmango: 3\n"
+	got := convertYAML(t, in, Options{})
+	want := `{"zebra":1,"apple":2,"mango":3}`
+	if got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+func TestConvertBytesSortKeys(t *testing.T) {
+	in := "zebra: 1\napple: 2\nmango: 3\n"
+	got := convertYAML(t, in, Options{SortKeys: true})
+	want := `{"apple":2,"mango":3,"zebra":1}`
+	if got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+func TestConvertBytesMergeKey(t *testing.T) {
+	in := "base: &base\n  a: 1\n  b: 2\nfoo:\n  <<: *base\n  c: 3\n"
+	got := convertYAML(t, in, Options{})
+	want := `{"base":{"a":1,"b":2},"foo":{"a":1,"b":2,"c":3}}`
+	if got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+func TestConvertBytesMergeKeySequence(t *testing.T) {
+	in := "a: &a\n  x: 1\n  y: 2\nb: &b\n  y: 99\n  z: 3\nmerged:\n  <<: [*a, *b]\n"
+	got := convertYAML(t, in, Options{})
+	want := `{"a":{"x":1,"y":2},"b":{"y":99,"z":3},"merged":{"x":1,"y":2,"z":3}}`
+	if got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+func TestConvertBytesMergeKeyExplicitKeyWins(t *testing.T) {
+	in := "a: &a\n  x: 1\nfoo:\n  <<: *a\n  x: 5\n"
+	got := convertYAML(t, in, Options{})
+	want := `{"a":{"x":1},"foo":{"x":5}}`
+	if got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+func TestConvertBytesDuplicateKeyLastWins(t *testing.T) {
+	in := "a: 1\na: 2\n"
+	got := convertYAML(t, in, Options{})
+	want := `{"a":2}`
+	if got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+func TestConvertBytesScalarTags(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"int", "v: 1\n", `{"v":1}`},
+		{"bigint", "v: 123456789012345678901234567890\n", `{"v":123456789012345678901234567890}`},
+		{"float-stays-float", "v: 1.0\n", `{"v":1.0}`},
+		{"float-trailing-zero", "v: 1.50\n", `{"v":1.50}`},
+		{"bool", "v: true\n", `{"v":true}`},
+		{"null", "v: null\n", `{"v":null}`},
+		{"string", "v: hello\n", `{"v":"hello"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := convertYAML(t, tt.in, Options{})
+			if got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConvertBytesMultiDoc(t *testing.T) {
+	in := "a: 1\n---\nb: 2\n"
+
+	gotArray := convertYAML(t, in, Options{})
+	wantArray := `[{"a":1},{"b":2}]`
+	if gotArray != wantArray {
+		t.Errorf("array form: got %s, want %s", gotArray, wantArray)
+	}
+
+	gotNDJSON := convertYAML(t, in, Options{NDJSON: true})
+	wantNDJSON := "{\"a\":1}\n{\"b\":2}\n"
+	if gotNDJSON != wantNDJSON {
+		t.Errorf("ndjson form: got %q, want %q", gotNDJSON, wantNDJSON)
+	}
+}
+
+func TestConvertBytesNDJSONWithIndent(t *testing.T) {
+	in := "a: 1\n---\nb: 2\n"
+	got := convertYAML(t, in, Options{NDJSON: true, Indent: 2})
+	want := "{\n  \"a\": 1\n}\n{\n  \"b\": 2\n}\n"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestConvertBytesEmptyStreamIsNull(t *testing.T) {
+	tests := []string{"", "\n", "# just a comment\n"}
+	for _, in := range tests {
+		t.Run(in, func(t *testing.T) {
+			got := convertYAML(t, in, Options{})
+			if got != "null" {
+				t.Errorf("got %q, want %q", got, "null")
+			}
+		})
+	}
+}
+
+func TestConvertBytesFormatIsIndependentOfDirection(t *testing.T) {
+	in := `{"b":2,"a":1}`
+
+	got := convertYAML(t, in, Options{Format: "json"})
+	want := `{"a":1,"b":2}`
+	if got != want {
+		t.Errorf("-format json without -reverse: got %s, want %s", got, want)
+	}
+
+	got = convertYAML(t, in, Options{Format: "json", Reverse: true})
+	wantYAML := "a: 1\nb: 2\n"
+	if got != wantYAML {
+		t.Errorf("-format json -reverse: got %q, want %q", got, wantYAML)
+	}
+}
+
+func TestConvertBytesRejectsInfAndNaN(t *testing.T) {
+	tests := []string{"v: .inf\n", "v: -.inf\n", "v: .nan\n"}
+	for _, in := range tests {
+		t.Run(in, func(t *testing.T) {
+			_, err := ConvertBytes([]byte(in), Options{})
+			if err == nil {
+				t.Fatalf("ConvertBytes(%q): expected an error, got nil", in)
+			}
+			if strings.Contains(err.Error(), "strconv.ParseFloat") {
+				t.Errorf("error leaks strconv internals: %v", err)
+			}
+		})
+	}
+}